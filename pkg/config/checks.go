@@ -0,0 +1,106 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/xeipuuv/gojsonschema"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// SchemaCheck is a single built-in or custom check: a JSON Schema evaluated
+// against some portion of a workload (or a standalone object).
+type SchemaCheck struct {
+	ID             string `yaml:"-" json:"-"`
+	Category       string `yaml:"category" json:"category"`
+	SuccessMessage string `yaml:"successMessage" json:"successMessage"`
+	FailureMessage string `yaml:"failureMessage" json:"failureMessage"`
+
+	// Target scopes which resolveCheck path offers this check to an
+	// object: Pod, Controller, Container, Other, or Service.
+	Target TargetKind `yaml:"target" json:"target"`
+	// SchemaTarget overrides what object Schema is actually evaluated
+	// against - e.g. a container-scoped check whose rule needs to see the
+	// whole PodSpec rather than a single Container.
+	SchemaTarget TargetKind `yaml:"schemaTarget" json:"schemaTarget"`
+
+	Schema map[string]interface{} `yaml:"schema" json:"schema"`
+
+	// Jsonnet, when set, is evaluated instead of Schema: the target object
+	// is injected as std.extVar("input") and the snippet must return either
+	// a bare boolean or an object {pass: bool, message: string}. See
+	// evaluateJsonnetCheck in pkg/validator for how the snippet is run and
+	// its result interpreted.
+	Jsonnet string `yaml:"jsonnet" json:"jsonnet"`
+}
+
+// IsActionable reports whether this check applies when resolving checks for
+// the given target/kind, e.g. a Container-scoped check shouldn't be offered
+// while resolving Pod-level checks.
+func (check SchemaCheck) IsActionable(target TargetKind, kind string, isInitContainer bool) bool {
+	if check.Target == "" {
+		return true
+	}
+	return check.Target == target
+}
+
+// validate runs this check's JSON Schema against obj, already converted to
+// a plain map/slice/scalar tree.
+func (check SchemaCheck) validate(obj interface{}) (bool, error) {
+	if check.Schema == nil {
+		return true, nil
+	}
+	schemaLoader := gojsonschema.NewGoLoader(check.Schema)
+	docLoader := gojsonschema.NewGoLoader(obj)
+	result, err := gojsonschema.Validate(schemaLoader, docLoader)
+	if err != nil {
+		return false, fmt.Errorf("Validating check %s failed: %v", check.ID, err)
+	}
+	return result.Valid(), nil
+}
+
+// CheckPod runs this check's schema against a PodSpec.
+func (check SchemaCheck) CheckPod(podSpec *corev1.PodSpec) (bool, error) {
+	asMap, err := toMap(podSpec)
+	if err != nil {
+		return false, err
+	}
+	return check.validate(asMap)
+}
+
+// CheckController runs this check's schema against a controller's raw JSON.
+func (check SchemaCheck) CheckController(originalObjectJSON []byte) (bool, error) {
+	asMap := map[string]interface{}{}
+	if err := json.Unmarshal(originalObjectJSON, &asMap); err != nil {
+		return false, fmt.Errorf("Unmarshaling controller JSON for check %s failed: %v", check.ID, err)
+	}
+	return check.validate(asMap)
+}
+
+// CheckContainer runs this check's schema against a single Container.
+func (check SchemaCheck) CheckContainer(container *corev1.Container) (bool, error) {
+	asMap, err := toMap(container)
+	if err != nil {
+		return false, err
+	}
+	return check.validate(asMap)
+}
+
+// CheckObject runs this check's schema against an arbitrary unstructured
+// object, e.g. a Service or a PodDisruptionBudget.
+func (check SchemaCheck) CheckObject(obj *unstructured.Unstructured) (bool, error) {
+	return check.validate(obj.Object)
+}
+
+func toMap(obj interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("Marshaling object failed: %v", err)
+	}
+	asMap := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &asMap); err != nil {
+		return nil, fmt.Errorf("Unmarshaling object failed: %v", err)
+	}
+	return asMap, nil
+}
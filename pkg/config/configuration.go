@@ -0,0 +1,61 @@
+package config
+
+// Configuration is the top-level Polaris configuration: which checks run,
+// at what severity, and the knobs that control how they're executed.
+type Configuration struct {
+	Checks             map[string]Severity    `yaml:"checks" json:"checks"`
+	CustomChecks       map[string]SchemaCheck `yaml:"customChecks" json:"customChecks"`
+	Exemptions         []Exemption            `yaml:"exemptions" json:"exemptions"`
+	DisallowExemptions bool                   `yaml:"disallowExemptions" json:"disallowExemptions"`
+
+	// Parallelism bounds how many checks are evaluated concurrently for a
+	// single workload (set via --parallelism). Zero or negative means "pick
+	// a default based on GOMAXPROCS".
+	Parallelism int `yaml:"parallelism" json:"parallelism"`
+
+	// EnforcementModes lets operators promote individual checks from
+	// advisory to blocking one at a time when Polaris runs as an admission
+	// webhook. Look up a check's mode via EnforcementModeFor rather than
+	// indexing this map directly, since an absent entry should default to
+	// EnforcementModeWarn, not to the zero value "".
+	EnforcementModes map[string]EnforcementMode `yaml:"enforcementModes" json:"enforcementModes"`
+}
+
+// Exemption excuses a specific controller/container from a set of checks,
+// independent of the polaris.fairwinds.com/exempt annotation.
+type Exemption struct {
+	Namespace      string   `yaml:"namespace" json:"namespace"`
+	ControllerName string   `yaml:"controllerName" json:"controllerName"`
+	ContainerNames []string `yaml:"containerNames" json:"containerNames"`
+	Rules          []string `yaml:"rules" json:"rules"`
+}
+
+// IsActionable reports whether checkID should run against the given
+// namespace/name/containerName, after accounting for configured
+// exemptions.
+func (conf Configuration) IsActionable(checkID, namespace, name, containerName string) bool {
+	for _, exemption := range conf.Exemptions {
+		if exemption.Namespace != "" && exemption.Namespace != namespace {
+			continue
+		}
+		if exemption.ControllerName != "" && exemption.ControllerName != name {
+			continue
+		}
+		if containerName != "" && len(exemption.ContainerNames) > 0 && !containsString(exemption.ContainerNames, containerName) {
+			continue
+		}
+		if containsString(exemption.Rules, checkID) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, val string) bool {
+	for _, item := range list {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package config
+
+// EnforcementMode controls what a failing check does when Polaris runs as
+// a ValidatingAdmissionWebhook.
+type EnforcementMode string
+
+const (
+	// EnforcementModeDeny rejects the admission request.
+	EnforcementModeDeny EnforcementMode = "deny"
+	// EnforcementModeWarn allows the request but surfaces the check's
+	// failure message as an admission warning.
+	EnforcementModeWarn EnforcementMode = "warn"
+	// EnforcementModeDryRun allows the request and only records the
+	// outcome in metrics, with no visible effect on the admission response.
+	EnforcementModeDryRun EnforcementMode = "dryrun"
+)
+
+// EnforcementModeFor returns the configured EnforcementMode for checkID,
+// defaulting to EnforcementModeWarn when unset or unrecognized so that a
+// check with no explicit entry - every check, on day one - is advisory
+// rather than silently invisible.
+func (conf Configuration) EnforcementModeFor(checkID string) EnforcementMode {
+	switch conf.EnforcementModes[checkID] {
+	case EnforcementModeDeny:
+		return EnforcementModeDeny
+	case EnforcementModeDryRun:
+		return EnforcementModeDryRun
+	default:
+		return EnforcementModeWarn
+	}
+}
@@ -0,0 +1,14 @@
+package config
+
+// Severity controls how a failing check is reported: as a blocking error,
+// an advisory warning, or ignored entirely.
+type Severity string
+
+const (
+	// SeverityError marks a failing check as blocking.
+	SeverityError Severity = "error"
+	// SeverityWarning marks a failing check as advisory only.
+	SeverityWarning Severity = "warning"
+	// SeverityIgnore excludes a check from results entirely.
+	SeverityIgnore Severity = "ignore"
+)
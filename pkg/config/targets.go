@@ -0,0 +1,21 @@
+package config
+
+// TargetKind indicates what portion of a workload (or which kind of
+// standalone object) a check's Schema is evaluated against.
+type TargetKind string
+
+const (
+	// TargetPod scopes a check to a PodSpec.
+	TargetPod TargetKind = "Pod"
+	// TargetController scopes a check to a controller's raw JSON (the
+	// Deployment/StatefulSet/etc. itself, not just its pod template).
+	TargetController TargetKind = "Controller"
+	// TargetContainer scopes a check to a single Container.
+	TargetContainer TargetKind = "Container"
+	// TargetOther scopes a check to an arbitrary unstructured object that
+	// isn't a pod-owning controller, e.g. a PodDisruptionBudget.
+	TargetOther TargetKind = "Other"
+	// TargetService scopes a check to a Service object, e.g. checks on
+	// spec.ipFamilies/ipFamilyPolicy.
+	TargetService TargetKind = "Service"
+)
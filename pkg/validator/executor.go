@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+// defaultParallelism is the fallback used when Configuration.Parallelism is
+// unset (zero or negative) and GOMAXPROCS does not report a positive value.
+const defaultParallelism = 4
+
+// parallelism returns how many checks may be evaluated concurrently for a
+// single workload, honoring Configuration.Parallelism (set via
+// --parallelism) when present.
+func parallelism(conf *config.Configuration) int {
+	if conf.Parallelism > 0 {
+		return conf.Parallelism
+	}
+	if n := runtime.GOMAXPROCS(0); n > 0 {
+		return n
+	}
+	return defaultParallelism
+}
+
+// checkEvaluator builds the work closure for a single checkID, or returns
+// ok=false if the check doesn't apply (exempted, not actionable, etc.) and
+// should be skipped entirely.
+type checkEvaluator func(checkID string) (run func() (ResultMessage, error), ok bool)
+
+// runChecksInParallel evaluates checkIDs for a single workload across a
+// worker pool sized by parallelism(conf). Because ResultSet is keyed by
+// check ID rather than ordered by a slice, results don't need to be
+// reassembled in evaluation order - each worker writes its own key, and the
+// deterministic ordering callers see comes from sorting keys at assembly
+// time (getSortedKeys), not from the order checks finished in.
+func runChecksInParallel(conf *config.Configuration, checkIDs []string, evaluate checkEvaluator) (ResultSet, error) {
+	type job struct {
+		checkID string
+		run     func() (ResultMessage, error)
+	}
+
+	jobs := make([]job, 0, len(checkIDs))
+	for _, checkID := range checkIDs {
+		run, ok := evaluate(checkID)
+		if !ok {
+			continue
+		}
+		jobs = append(jobs, job{checkID: checkID, run: run})
+	}
+
+	results := ResultSet{}
+	if len(jobs) == 0 {
+		return results, nil
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	sem := make(chan struct{}, parallelism(conf))
+	for _, j := range jobs {
+		j := j
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := j.run()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			results[j.checkID] = result
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
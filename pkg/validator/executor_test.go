@@ -0,0 +1,72 @@
+package validator
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+)
+
+// simulatedCheckCost stands in for the I/O/CPU cost of evaluating a single
+// check - just large enough that goroutine scheduling overhead doesn't
+// dominate the benchmark.
+const simulatedCheckCost = 200 * time.Microsecond
+
+func benchmarkRunChecksInParallel(b *testing.B, parallelism, numChecks int) {
+	conf := &config.Configuration{Parallelism: parallelism}
+	checkIDs := make([]string, numChecks)
+	for i := range checkIDs {
+		checkIDs[i] = fmt.Sprintf("check-%d", i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := runChecksInParallel(conf, checkIDs, func(checkID string) (func() (ResultMessage, error), bool) {
+			return func() (ResultMessage, error) {
+				time.Sleep(simulatedCheckCost)
+				return ResultMessage{ID: checkID, Success: true}, nil
+			}, true
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRunChecksInParallel_Serial approximates the pre-worker-pool
+// behavior (Parallelism: 1) as a baseline for the scaling benchmarks below.
+func BenchmarkRunChecksInParallel_Serial(b *testing.B) { benchmarkRunChecksInParallel(b, 1, 20) }
+
+func BenchmarkRunChecksInParallel_Parallelism4(b *testing.B) {
+	benchmarkRunChecksInParallel(b, 4, 20)
+}
+
+func BenchmarkRunChecksInParallel_Parallelism8(b *testing.B) {
+	benchmarkRunChecksInParallel(b, 8, 20)
+}
+
+// TestBuiltInChecksSafeForConcurrentReads exercises builtInChecks and
+// schemaBox - both populated once in init() and never written to again -
+// from many goroutines at once. Run with `go test -race` to confirm the
+// worker pool introduced by runChecksInParallel can read them concurrently
+// without a data race.
+func TestBuiltInChecksSafeForConcurrentReads(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, checkID := range checkOrder {
+				if _, ok := builtInChecks[checkID]; !ok {
+					t.Errorf("expected built-in check %s to be loaded", checkID)
+				}
+				if _, err := loadBuiltInCheck(checkID); err != nil {
+					t.Errorf("expected schemaBox to serve check %s: %v", checkID, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
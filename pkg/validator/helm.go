@@ -0,0 +1,203 @@
+package validator
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+// HelmResult attaches the chart template file that produced a ResultMessage,
+// and the 1-indexed line its document starts on within that template's
+// rendered output, so a failing check can be pointed back at the template
+// rather than only the combined rendered manifest.
+type HelmResult struct {
+	ResultMessage
+	TemplateFile string
+	Line         int
+}
+
+// ValidateHelmChart renders chartPath (a chart directory or a packaged
+// .tgz) the same way `helm template` would, using the given values files as
+// overrides, and runs the existing schema checks against every object the
+// chart produces. This lets CI gate a chart before `helm install` without
+// needing a live cluster.
+func ValidateHelmChart(conf *config.Configuration, chartPath string, valuesFiles []string) ([]HelmResult, error) {
+	chrt, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("Loading Helm chart %s failed: %v", chartPath, err)
+	}
+
+	vals, err := mergeHelmValues(valuesFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	renderVals, err := chartutil.ToRenderValues(chrt, vals, chartutil.ReleaseOptions{
+		Name:      chrt.Name(),
+		Namespace: "default",
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Computing Helm render values for %s failed: %v", chrt.Name(), err)
+	}
+
+	rendered, err := engine.Render(chrt, renderVals)
+	if err != nil {
+		return nil, fmt.Errorf("Rendering Helm chart %s failed: %v", chrt.Name(), err)
+	}
+
+	results := []HelmResult{}
+	for templateFile, manifest := range rendered {
+		if strings.TrimSpace(manifest) == "" || strings.HasSuffix(templateFile, "NOTES.txt") {
+			continue
+		}
+		templateResults, err := validateRenderedManifest(conf, templateFile, manifest)
+		if err != nil {
+			return nil, fmt.Errorf("Validating rendered template %s failed: %v", templateFile, err)
+		}
+		results = append(results, templateResults...)
+	}
+	return results, nil
+}
+
+// mergeHelmValues reads each values file in order and coalesces them into a
+// single values map, mirroring how `helm template -f a.yaml -f b.yaml`
+// layers later files over earlier ones.
+func mergeHelmValues(valuesFiles []string) (map[string]interface{}, error) {
+	vals := map[string]interface{}{}
+	for _, f := range valuesFiles {
+		overrides, err := chartutil.ReadValuesFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("Reading values file %s failed: %v", f, err)
+		}
+		vals = chartutil.CoalesceTables(overrides, vals)
+	}
+	return vals, nil
+}
+
+// validateRenderedManifest splits a single rendered template's multi-doc
+// YAML output and, for each document, runs the same checks the
+// Kubernetes-API-backed path runs: the full Pod/Controller/Container suite
+// for a pod-owning workload (Deployment, StatefulSet, ...), or
+// applyOtherSchemaChecks for anything else (Service, ConfigMap, PDB, ...) -
+// mirroring evaluateAdmissionResults' dispatch in webhook.go. The starting
+// line of each document is kept so failures can be attributed back to the
+// chart/template file and line.
+func validateRenderedManifest(conf *config.Configuration, templateFile, manifest string) ([]HelmResult, error) {
+	attributed := []HelmResult{}
+	for _, doc := range splitYAMLDocs(manifest) {
+		if strings.TrimSpace(doc.Content) == "" {
+			continue
+		}
+		unst, err := parseUnstructuredDoc(doc.Content)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing %s:%d failed: %v", templateFile, doc.StartLine, err)
+		}
+
+		if !podOwningKinds[unst.GetKind()] {
+			otherResults, err := applyOtherSchemaChecks(conf, unst)
+			if err != nil {
+				return nil, err
+			}
+			attributed = append(attributed, attributeResults(otherResults, templateFile, doc.StartLine)...)
+			continue
+		}
+
+		workload, err := kube.NewGenericWorkload([]byte(doc.Content))
+		if err != nil {
+			return nil, fmt.Errorf("Parsing %s:%d failed: %v", templateFile, doc.StartLine, err)
+		}
+
+		podResults, err := applyPodSchemaChecks(conf, workload)
+		if err != nil {
+			return nil, err
+		}
+		attributed = append(attributed, attributeResults(podResults, templateFile, doc.StartLine)...)
+
+		ctrlResults, err := applyControllerSchemaChecks(conf, workload)
+		if err != nil {
+			return nil, err
+		}
+		attributed = append(attributed, attributeResults(ctrlResults, templateFile, doc.StartLine)...)
+
+		for i := range workload.PodSpec.Containers {
+			containerResults, err := applyContainerSchemaChecks(conf, workload, &workload.PodSpec.Containers[i], false)
+			if err != nil {
+				return nil, err
+			}
+			attributed = append(attributed, attributeResults(containerResults, templateFile, doc.StartLine)...)
+		}
+		for i := range workload.PodSpec.InitContainers {
+			containerResults, err := applyContainerSchemaChecks(conf, workload, &workload.PodSpec.InitContainers[i], true)
+			if err != nil {
+				return nil, err
+			}
+			attributed = append(attributed, attributeResults(containerResults, templateFile, doc.StartLine)...)
+		}
+	}
+	return attributed, nil
+}
+
+// parseUnstructuredDoc decodes a single YAML document into an
+// unstructured.Unstructured so its Kind can be inspected before deciding
+// which checks apply, the same way webhook.go does for an admitted object.
+func parseUnstructuredDoc(content string) (*unstructured.Unstructured, error) {
+	unst := &unstructured.Unstructured{}
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(content), 4096)
+	if err := decoder.Decode(unst); err != nil {
+		return nil, err
+	}
+	return unst, nil
+}
+
+func attributeResults(results ResultSet, templateFile string, line int) []HelmResult {
+	attributed := make([]HelmResult, 0, len(results))
+	for _, result := range results {
+		attributed = append(attributed, HelmResult{
+			ResultMessage: result,
+			TemplateFile:  templateFile,
+			Line:          line,
+		})
+	}
+	return attributed
+}
+
+// yamlDoc is one `---`-separated document from a rendered template, along
+// with the 1-indexed line it starts on within that template's output.
+type yamlDoc struct {
+	Content   string
+	StartLine int
+}
+
+// splitYAMLDocs splits a multi-doc YAML string the way the Helm/kubectl
+// tooling does, tracking line numbers as it goes so callers can attribute
+// errors back to a specific document.
+func splitYAMLDocs(manifest string) []yamlDoc {
+	docs := []yamlDoc{}
+	var builder strings.Builder
+	lineNum := 0
+	startLine := 1
+	scanner := bufio.NewScanner(strings.NewReader(manifest))
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if line == "---" {
+			docs = append(docs, yamlDoc{Content: builder.String(), StartLine: startLine})
+			builder.Reset()
+			startLine = lineNum + 1
+			continue
+		}
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+	docs = append(docs, yamlDoc{Content: builder.String(), StartLine: startLine})
+	return docs
+}
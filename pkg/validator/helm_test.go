@@ -0,0 +1,48 @@
+package validator
+
+import "testing"
+
+func TestSplitYAMLDocs(t *testing.T) {
+	manifest := "a: 1\n---\nb: 2\n---\nc: 3\n"
+	docs := splitYAMLDocs(manifest)
+	if len(docs) != 3 {
+		t.Fatalf("expected 3 docs, got %d", len(docs))
+	}
+	if docs[0].Content != "a: 1\n" || docs[0].StartLine != 1 {
+		t.Errorf("unexpected first doc: %+v", docs[0])
+	}
+	if docs[1].Content != "b: 2\n" || docs[1].StartLine != 3 {
+		t.Errorf("unexpected second doc: %+v", docs[1])
+	}
+	if docs[2].Content != "c: 3\n" || docs[2].StartLine != 5 {
+		t.Errorf("unexpected third doc: %+v", docs[2])
+	}
+}
+
+// TestSplitYAMLDocs_IndentedSeparatorInBlockScalar is a regression test for
+// splitting on strings.TrimSpace(line) == "---": an indented "---" inside a
+// block scalar (e.g. a ConfigMap embedding another YAML manifest) is literal
+// content, not a document separator, and must survive in a single document.
+func TestSplitYAMLDocs_IndentedSeparatorInBlockScalar(t *testing.T) {
+	manifest := "data: |\n  first\n  ---\n  second\n"
+	docs := splitYAMLDocs(manifest)
+	if len(docs) != 1 {
+		t.Fatalf("expected 1 doc, got %d: %+v", len(docs), docs)
+	}
+	if docs[0].Content != manifest {
+		t.Errorf("expected block scalar content to survive unchanged, got %q", docs[0].Content)
+	}
+}
+
+func TestParseUnstructuredDoc(t *testing.T) {
+	unst, err := parseUnstructuredDoc("apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n")
+	if err != nil {
+		t.Fatalf("parseUnstructuredDoc returned error: %v", err)
+	}
+	if unst.GetKind() != "Service" {
+		t.Errorf("expected Kind Service, got %q", unst.GetKind())
+	}
+	if unst.GetName() != "web" {
+		t.Errorf("expected name web, got %q", unst.GetName())
+	}
+}
@@ -0,0 +1,51 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonnet "github.com/google/go-jsonnet"
+)
+
+// jsonnetResult is the shape a Jsonnet check is allowed to return: either a
+// bare boolean, or an object carrying both the pass/fail outcome and a
+// message to use in place of the check's configured FailureMessage.
+type jsonnetResult struct {
+	Pass    bool   `json:"pass"`
+	Message string `json:"message"`
+}
+
+// evaluateJsonnetCheck runs a Jsonnet-authored check against target,
+// injecting target as std.extVar("input"). checkJsonnetOrSchema in
+// schema.go calls this instead of a check's own CheckPod/CheckController/
+// CheckContainer/CheckObject whenever check.Jsonnet is set; target must
+// already be a value json.Marshal can turn into the object the snippet
+// expects - a []byte is marshaled as a base64 string, not decoded first, so
+// callers need to unmarshal raw JSON into a map before passing it in here.
+func evaluateJsonnetCheck(snippet string, target interface{}) (passes bool, message string, err error) {
+	inputJSON, err := json.Marshal(target)
+	if err != nil {
+		return false, "", fmt.Errorf("Marshaling target for Jsonnet check failed: %v", err)
+	}
+
+	vm := jsonnet.MakeVM()
+	vm.ExtCode("input", string(inputJSON))
+
+	output, err := vm.EvaluateAnonymousSnippet("check.jsonnet", snippet)
+	if err != nil {
+		return false, "", fmt.Errorf("Evaluating Jsonnet check failed: %v", err)
+	}
+
+	// A Jsonnet check may return a bare boolean...
+	var boolResult bool
+	if err := json.Unmarshal([]byte(output), &boolResult); err == nil {
+		return boolResult, "", nil
+	}
+
+	// ...or an object with an explicit message.
+	var objResult jsonnetResult
+	if err := json.Unmarshal([]byte(output), &objResult); err != nil {
+		return false, "", fmt.Errorf("Jsonnet check must return a bool or {pass, message}, got: %s", output)
+	}
+	return objResult.Pass, objResult.Message, nil
+}
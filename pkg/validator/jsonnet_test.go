@@ -0,0 +1,48 @@
+package validator
+
+import "testing"
+
+// TestEvaluateJsonnetCheck_ObjectInput confirms a snippet can walk a decoded
+// map via std.objectHas and std.extVar("input"), and that a {pass, message}
+// result surfaces both fields.
+func TestEvaluateJsonnetCheck_ObjectInput(t *testing.T) {
+	target := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": 1,
+		},
+	}
+	snippet := `
+local input = std.extVar('input');
+if std.objectHas(input, 'spec') && input.spec.replicas > 1 then
+  { pass: true }
+else
+  { pass: false, message: 'replicas must be greater than 1' }
+`
+	passes, message, err := evaluateJsonnetCheck(snippet, target)
+	if err != nil {
+		t.Fatalf("evaluateJsonnetCheck returned error: %v", err)
+	}
+	if passes {
+		t.Errorf("expected passes=false for replicas=1")
+	}
+	if message != "replicas must be greater than 1" {
+		t.Errorf("unexpected message: %q", message)
+	}
+}
+
+// TestEvaluateJsonnetCheck_RawBytesFail documents why schema.go must
+// unmarshal a controller's raw JSON into a map before calling
+// evaluateJsonnetCheck: json.Marshal encodes a []byte as a base64 string
+// rather than the object it holds, so a snippet that expects to walk an
+// object sees an opaque string and fails instead of evaluating the rule.
+func TestEvaluateJsonnetCheck_RawBytesFail(t *testing.T) {
+	rawJSON := []byte(`{"spec":{"replicas":1}}`)
+	snippet := `
+local input = std.extVar('input');
+if std.objectHas(input, 'spec') then { pass: true } else { pass: false }
+`
+	_, _, err := evaluateJsonnetCheck(snippet, rawJSON)
+	if err == nil {
+		t.Fatalf("expected evaluateJsonnetCheck to fail when given raw []byte instead of a decoded map")
+	}
+}
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"sort"
@@ -27,6 +28,7 @@ var (
 	checkOrder = []string{
 		// Controller Checks
 		"multipleReplicasForDeployment",
+		"rollingUpdateMaxUnavailableInvalid",
 		// Pod checks
 		"hostIPCSet",
 		"hostPIDSet",
@@ -51,17 +53,17 @@ var (
 		// Other checks
 		"tlsSettingsMissing",
 		"pdbDisruptionsAllowedGreaterThanZero",
+		// Dual-stack / IPv6 checks
+		"hostNetworkDualStackMisconfigured",
+		"podSpecHardcodedIPFamily",
+		"serviceMissingIPFamilyPolicy",
 	}
 )
 
 func init() {
 	schemaBox = packr.New("Schemas", "../../checks")
 	for _, checkID := range checkOrder {
-		contents, err := schemaBox.Find(checkID + ".yaml")
-		if err != nil {
-			panic(err)
-		}
-		check, err := parseCheck(contents)
+		check, err := loadBuiltInCheck(checkID)
 		if err != nil {
 			panic(err)
 		}
@@ -70,6 +72,38 @@ func init() {
 	}
 }
 
+// loadBuiltInCheck loads checkID's definition from ../../checks, preferring
+// a YAML/JSON-schema file (checkID.yaml) and falling back to a standalone
+// Jsonnet-authored one (checkID.jsonnet) when no schema file exists.
+func loadBuiltInCheck(checkID string) (config.SchemaCheck, error) {
+	if contents, err := schemaBox.Find(checkID + ".yaml"); err == nil {
+		return parseCheck(contents)
+	}
+	contents, err := schemaBox.Find(checkID + ".jsonnet")
+	if err != nil {
+		return config.SchemaCheck{}, fmt.Errorf("No .yaml or .jsonnet definition found for check %s: %v", checkID, err)
+	}
+	return parseJsonnetCheck(checkID, contents)
+}
+
+// parseJsonnetCheck wraps a standalone Jsonnet rule file as a SchemaCheck.
+// Unlike the YAML/JSON-schema checks, a .jsonnet check file carries no
+// separate metadata, so it gets generic messages; a check that needs a
+// specific message should return {pass, message} from the snippet itself
+// (see evaluateJsonnetCheck). Jsonnet checks are evaluated at the
+// controller level: that's the one target whose full object graph is
+// available, and a standalone check has no companion YAML to tell us which
+// narrower target (Pod/Container/Other) it actually wants.
+func parseJsonnetCheck(checkID string, rawBytes []byte) (config.SchemaCheck, error) {
+	return config.SchemaCheck{
+		Category:       "Custom",
+		SuccessMessage: fmt.Sprintf("%s passed", checkID),
+		FailureMessage: fmt.Sprintf("%s failed", checkID),
+		Target:         config.TargetController,
+		Jsonnet:        string(rawBytes),
+	}, nil
+}
+
 func parseCheck(rawBytes []byte) (config.SchemaCheck, error) {
 	reader := bytes.NewReader(rawBytes)
 	check := config.SchemaCheck{}
@@ -105,20 +139,46 @@ func resolveCheck(conf *config.Configuration, checkID, kind string, target confi
 }
 
 func makeResult(conf *config.Configuration, check *config.SchemaCheck, passes bool) ResultMessage {
+	return makeResultWithMessage(conf, check, passes, "")
+}
+
+// makeResultWithMessage builds a ResultMessage the same way makeResult does,
+// but substitutes overrideMessage for the check's configured
+// Success/FailureMessage when one is given - e.g. a Jsonnet check that
+// returned {pass, message} rather than a bare boolean.
+func makeResultWithMessage(conf *config.Configuration, check *config.SchemaCheck, passes bool, overrideMessage string) ResultMessage {
 	result := ResultMessage{
 		ID:       check.ID,
 		Severity: conf.Checks[check.ID],
 		Category: check.Category,
 		Success:  passes,
 	}
-	if passes {
+	switch {
+	case overrideMessage != "":
+		result.Message = overrideMessage
+	case passes:
 		result.Message = check.SuccessMessage
-	} else {
+	default:
 		result.Message = check.FailureMessage
 	}
 	return result
 }
 
+// checkJsonnetOrSchema evaluates check against target, preferring its
+// Jsonnet snippet (check.Jsonnet) when one is configured and falling back
+// to schemaCheck, the existing JSON-schema-backed check, otherwise. Callers
+// pass target as a value json.Marshal turns into the object itself (a
+// PodSpec, a Container, an Unstructured, a decoded map, ...); for a
+// controller that is the decoded map, not the raw []byte its schemaCheck
+// closure hands to CheckController.
+func checkJsonnetOrSchema(check *config.SchemaCheck, target interface{}, schemaCheck func() (bool, error)) (passes bool, overrideMessage string, err error) {
+	if check.Jsonnet != "" {
+		return evaluateJsonnetCheck(check.Jsonnet, target)
+	}
+	passes, err = schemaCheck()
+	return passes, "", err
+}
+
 const exemptionAnnotationKey = "polaris.fairwinds.com/exempt"
 const exemptionAnnotationPattern = "polaris.fairwinds.com/%s-exempt"
 
@@ -137,104 +197,147 @@ func hasExemptionAnnotation(ctrl kube.GenericWorkload, checkID string) bool {
 }
 
 func applyPodSchemaChecks(conf *config.Configuration, controller kube.GenericWorkload) (ResultSet, error) {
-	results := ResultSet{}
 	checkIDs := getSortedKeys(conf.Checks)
-	for _, checkID := range checkIDs {
+	return runChecksInParallel(conf, checkIDs, func(checkID string) (func() (ResultMessage, error), bool) {
 		if !conf.DisallowExemptions && hasExemptionAnnotation(controller, checkID) {
-			continue
+			return nil, false
 		}
 		check, err := resolveCheck(conf, checkID, controller.Kind, config.TargetPod, controller.ObjectMeta, "", false)
-
 		if err != nil {
-			return nil, err
+			return func() (ResultMessage, error) { return ResultMessage{}, err }, true
 		} else if check == nil {
-			continue
+			return nil, false
 		}
-		passes, err := check.CheckPod(&controller.PodSpec)
-		if err != nil {
-			return nil, err
-		}
-		results[check.ID] = makeResult(conf, check, passes)
-	}
-	return results, nil
+		return func() (ResultMessage, error) {
+			passes, overrideMessage, err := checkJsonnetOrSchema(check, &controller.PodSpec, func() (bool, error) {
+				return check.CheckPod(&controller.PodSpec)
+			})
+			if err != nil {
+				return ResultMessage{}, err
+			}
+			return makeResultWithMessage(conf, check, passes, overrideMessage), nil
+		}, true
+	})
 }
 
 func applyControllerSchemaChecks(conf *config.Configuration, controller kube.GenericWorkload) (ResultSet, error) {
-	results := ResultSet{}
 	checkIDs := getSortedKeys(conf.Checks)
-	for _, checkID := range checkIDs {
+	// Decoded once per workload, up front, rather than inside the
+	// per-check closures below - those closures run concurrently on
+	// runChecksInParallel's worker pool, so a lazily-decoded shared map
+	// would race. A Jsonnet check needs the controller as a plain map so
+	// std.extVar("input") can walk its fields; json.Marshal-ing the raw
+	// []byte itself would just base64-encode it as an opaque string
+	// instead of the object it holds.
+	decoded, decodeErr := unmarshalControllerJSON(controller.OriginalObjectJSON)
+	return runChecksInParallel(conf, checkIDs, func(checkID string) (func() (ResultMessage, error), bool) {
 		if !conf.DisallowExemptions && hasExemptionAnnotation(controller, checkID) {
-			continue
+			return nil, false
 		}
 		check, err := resolveCheck(conf, checkID, controller.Kind, config.TargetController, controller.ObjectMeta, "", false)
-
 		if err != nil {
-			return nil, err
+			return func() (ResultMessage, error) { return ResultMessage{}, err }, true
 		} else if check == nil {
-			continue
-		}
-		passes, err := check.CheckController(controller.OriginalObjectJSON)
-		if err != nil {
-			return nil, err
+			return nil, false
 		}
-		results[check.ID] = makeResult(conf, check, passes)
+		return func() (ResultMessage, error) {
+			var jsonnetTarget interface{} = controller.OriginalObjectJSON
+			if check.Jsonnet != "" {
+				if decodeErr != nil {
+					return ResultMessage{}, fmt.Errorf("Decoding controller object for Jsonnet check %s failed: %v", checkID, decodeErr)
+				}
+				jsonnetTarget = decoded
+			}
+			passes, overrideMessage, err := checkJsonnetOrSchema(check, jsonnetTarget, func() (bool, error) {
+				return check.CheckController(controller.OriginalObjectJSON)
+			})
+			if err != nil {
+				return ResultMessage{}, err
+			}
+			return makeResultWithMessage(conf, check, passes, overrideMessage), nil
+		}, true
+	})
+}
+
+// unmarshalControllerJSON decodes a controller's raw JSON into a plain map
+// for Jsonnet checks to traverse via std.extVar("input").
+// applyControllerSchemaChecks calls it once per workload, whether or not any
+// Jsonnet-backed check applies; the schema-backed path only ever uses the
+// original []byte.
+func unmarshalControllerJSON(raw []byte) (map[string]interface{}, error) {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, err
 	}
-	return results, nil
+	return decoded, nil
 }
 
 func applyContainerSchemaChecks(conf *config.Configuration, controller kube.GenericWorkload, container *corev1.Container, isInit bool) (ResultSet, error) {
-	results := ResultSet{}
 	checkIDs := getSortedKeys(conf.Checks)
-	for _, checkID := range checkIDs {
+	return runChecksInParallel(conf, checkIDs, func(checkID string) (func() (ResultMessage, error), bool) {
 		if !conf.DisallowExemptions && hasExemptionAnnotation(controller, checkID) {
-			continue
+			return nil, false
 		}
 		check, err := resolveCheck(conf, checkID, controller.Kind, config.TargetContainer, controller.ObjectMeta, container.Name, isInit)
-
 		if err != nil {
-			return nil, err
+			return func() (ResultMessage, error) { return ResultMessage{}, err }, true
 		} else if check == nil {
-			continue
-		}
-		var passes bool
-		if check.SchemaTarget == config.TargetPod {
-			podCopy := controller.PodSpec
-			podCopy.InitContainers = []corev1.Container{}
-			podCopy.Containers = []corev1.Container{*container}
-			passes, err = check.CheckPod(&podCopy)
-		} else {
-			passes, err = check.CheckContainer(container)
-		}
-		if err != nil {
-			return nil, err
+			return nil, false
 		}
-		results[check.ID] = makeResult(conf, check, passes)
-	}
-	return results, nil
+		return func() (ResultMessage, error) {
+			var passes bool
+			var overrideMessage string
+			var err error
+			if check.SchemaTarget == config.TargetPod {
+				podCopy := controller.PodSpec
+				podCopy.InitContainers = []corev1.Container{}
+				podCopy.Containers = []corev1.Container{*container}
+				passes, overrideMessage, err = checkJsonnetOrSchema(check, &podCopy, func() (bool, error) {
+					return check.CheckPod(&podCopy)
+				})
+			} else {
+				passes, overrideMessage, err = checkJsonnetOrSchema(check, container, func() (bool, error) {
+					return check.CheckContainer(container)
+				})
+			}
+			if err != nil {
+				return ResultMessage{}, err
+			}
+			return makeResultWithMessage(conf, check, passes, overrideMessage), nil
+		}, true
+	})
 }
 
 func applyOtherSchemaChecks(conf *config.Configuration, unst *unstructured.Unstructured) (ResultSet, error) {
-	results := ResultSet{}
 	checkIDs := getSortedKeys(conf.Checks)
 	objMeta, err := meta.Accessor(unst)
 	if err != nil {
-		return results, err
+		return ResultSet{}, err
 	}
-	for _, checkID := range checkIDs {
-		check, err := resolveCheck(conf, checkID, unst.GetKind(), "", objMeta, "", false)
-
+	target := config.TargetKind("")
+	if unst.GetKind() == "Service" {
+		// Scoped separately from the rest of applyOtherSchemaChecks so that
+		// Service-only checks (ipFamilies/ipFamilyPolicy) aren't offered to
+		// every other kind that flows through here.
+		target = config.TargetService
+	}
+	return runChecksInParallel(conf, checkIDs, func(checkID string) (func() (ResultMessage, error), bool) {
+		check, err := resolveCheck(conf, checkID, unst.GetKind(), target, objMeta, "", false)
 		if err != nil {
-			return nil, err
+			return func() (ResultMessage, error) { return ResultMessage{}, err }, true
 		} else if check == nil {
-			continue
-		}
-		passes, err := check.CheckObject(unst)
-		if err != nil {
-			return nil, err
+			return nil, false
 		}
-		results[check.ID] = makeResult(conf, check, passes)
-	}
-	return results, nil
+		return func() (ResultMessage, error) {
+			passes, overrideMessage, err := checkJsonnetOrSchema(check, unst, func() (bool, error) {
+				return check.CheckObject(unst)
+			})
+			if err != nil {
+				return ResultMessage{}, err
+			}
+			return makeResultWithMessage(conf, check, passes, overrideMessage), nil
+		}, true
+	})
 }
 
 func applyArbitrarySchemaChecks(conf *config.Configuration, unst *unstructured.Unstructured) (ResultSet, error) {
@@ -252,11 +355,13 @@ func applyArbitrarySchemaChecks(conf *config.Configuration, unst *unstructured.U
 		} else if check == nil {
 			continue
 		}
-		passes, err := check.CheckObject(unst)
+		passes, overrideMessage, err := checkJsonnetOrSchema(check, unst, func() (bool, error) {
+			return check.CheckObject(unst)
+		})
 		if err != nil {
 			return nil, err
 		}
-		results[check.ID] = makeResult(conf, check, passes)
+		results[check.ID] = makeResultWithMessage(conf, check, passes, overrideMessage)
 	}
 	return results, nil
 }
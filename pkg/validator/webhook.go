@@ -0,0 +1,182 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	admissionv1 "k8s.io/api/admission/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fairwindsops/polaris/pkg/config"
+	"github.com/fairwindsops/polaris/pkg/kube"
+)
+
+// checkOutcomesTotal tracks how often each check passes or fails, broken
+// down by check and namespace, whatever the check's EnforcementMode, so
+// operators can watch failure rates before promoting a check from warn to
+// deny.
+var checkOutcomesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "polaris_admission_check_outcomes_total",
+	Help: "Count of Polaris admission webhook check outcomes by check ID, namespace, and outcome.",
+}, []string{"check_id", "namespace", "outcome"})
+
+func init() {
+	prometheus.MustRegister(checkOutcomesTotal)
+}
+
+// podOwningKinds are the controller kinds whose PodSpec/containers get the
+// full Pod/Controller/Container check treatment in the admission path;
+// anything else is routed through applyOtherSchemaChecks.
+var podOwningKinds = map[string]bool{
+	"Pod":         true,
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"Job":         true,
+	"CronJob":     true,
+	"ReplicaSet":  true,
+}
+
+// HandleAdmissionReview runs the full set of schema checks against the
+// object in review.Request and turns the results into an AdmissionReview
+// response, honoring each check's EnforcementMode:
+//   - deny:   a failure sets allowed=false with the check's FailureMessage
+//   - warn:   a failure is added to response.warnings, allowed stays true
+//   - dryrun: a failure is only recorded in the check-outcome metrics
+func HandleAdmissionReview(conf *config.Configuration, review *admissionv1.AdmissionReview) (*admissionv1.AdmissionReview, error) {
+	req := review.Request
+	if req == nil {
+		return nil, fmt.Errorf("AdmissionReview has no Request")
+	}
+
+	unst := &unstructured.Unstructured{}
+	if err := json.Unmarshal(req.Object.Raw, unst); err != nil {
+		return nil, fmt.Errorf("Unmarshaling admission object failed: %v", err)
+	}
+
+	results, err := evaluateAdmissionResults(conf, req.Object.Raw, unst)
+	if err != nil {
+		return nil, fmt.Errorf("Evaluating checks for admission review failed: %v", err)
+	}
+
+	response := &admissionv1.AdmissionResponse{
+		UID:     req.UID,
+		Allowed: true,
+	}
+
+	denyMessages := []string{}
+	for _, result := range results {
+		outcome := "pass"
+		if !result.Success {
+			outcome = "fail"
+		}
+		checkOutcomesTotal.WithLabelValues(result.ID, req.Namespace, outcome).Inc()
+
+		if result.Success {
+			continue
+		}
+		switch conf.EnforcementModeFor(result.ID) {
+		case config.EnforcementModeDeny:
+			response.Allowed = false
+			denyMessages = append(denyMessages, result.Message)
+		case config.EnforcementModeDryRun:
+			// Outcome already recorded above; no effect on admission.
+		default:
+			// EnforcementModeWarn, and the default for any check with no
+			// explicit entry in conf.EnforcementModes - see
+			// Configuration.EnforcementModeFor.
+			response.Warnings = append(response.Warnings, result.Message)
+		}
+	}
+
+	// Join rather than keep only the last denial, so an object failing
+	// several deny-mode checks at once doesn't hide all but one of them
+	// from the requester - the metrics above already record every outcome,
+	// but the admission response is the only place a human actually reads.
+	if len(denyMessages) > 0 {
+		response.Result = &metaV1.Status{
+			Message: strings.Join(denyMessages, "; "),
+			Reason:  metaV1.StatusReasonForbidden,
+		}
+	}
+
+	return &admissionv1.AdmissionReview{
+		TypeMeta: review.TypeMeta,
+		Response: response,
+	}, nil
+}
+
+// evaluateAdmissionResults runs every applicable check - pod, controller,
+// every container, or the generic "other" checks - against a single
+// admitted object, the same way the CLI audit path covers a
+// Kubernetes-API-backed workload.
+func evaluateAdmissionResults(conf *config.Configuration, rawObject []byte, unst *unstructured.Unstructured) (ResultSet, error) {
+	results := ResultSet{}
+
+	if !podOwningKinds[unst.GetKind()] {
+		otherResults, err := applyOtherSchemaChecks(conf, unst)
+		if err != nil {
+			return nil, err
+		}
+		mergeResults(results, otherResults)
+		return results, nil
+	}
+
+	workload, err := kube.NewGenericWorkload(rawObject)
+	if err != nil {
+		return nil, fmt.Errorf("Parsing %s for admission review failed: %v", unst.GetKind(), err)
+	}
+
+	podResults, err := applyPodSchemaChecks(conf, workload)
+	if err != nil {
+		return nil, err
+	}
+	mergeResults(results, podResults)
+
+	ctrlResults, err := applyControllerSchemaChecks(conf, workload)
+	if err != nil {
+		return nil, err
+	}
+	mergeResults(results, ctrlResults)
+
+	for i := range workload.PodSpec.Containers {
+		containerResults, err := applyContainerSchemaChecks(conf, workload, &workload.PodSpec.Containers[i], false)
+		if err != nil {
+			return nil, err
+		}
+		mergeContainerResults(results, containerResults)
+	}
+	for i := range workload.PodSpec.InitContainers {
+		containerResults, err := applyContainerSchemaChecks(conf, workload, &workload.PodSpec.InitContainers[i], true)
+		if err != nil {
+			return nil, err
+		}
+		mergeContainerResults(results, containerResults)
+	}
+
+	return results, nil
+}
+
+// mergeResults copies src into dst, used where a checkID can only be
+// evaluated once per object (pod-level, controller-level, "other").
+func mergeResults(dst, src ResultSet) {
+	for id, result := range src {
+		dst[id] = result
+	}
+}
+
+// mergeContainerResults folds a single container's results into dst. A
+// workload should be denied/warned if ANY of its containers fails a check,
+// so a failing result always wins over a previously-recorded passing one
+// for the same checkID.
+func mergeContainerResults(dst, src ResultSet) {
+	for id, result := range src {
+		existing, ok := dst[id]
+		if !ok || (existing.Success && !result.Success) {
+			dst[id] = result
+		}
+	}
+}